@@ -0,0 +1,45 @@
+package config
+
+import (
+	"context"
+	"testing"
+)
+
+type staticSecretProvider struct{ plaintext string }
+
+func (p staticSecretProvider) Decrypt(context.Context, []byte) ([]byte, error) {
+	return []byte(p.plaintext), nil
+}
+
+// TestLoader_Describe_RedactsDecryptedSecrets confirms Describe().Values
+// never leaks a decrypted secret's plaintext, since it's a debugging
+// introspection surface ("why is this value X in staging?") rather than a
+// credential store.
+func TestLoader_Describe_RedactsDecryptedSecrets(t *testing.T) {
+	RegisterSecretProvider("test-describe", staticSecretProvider{plaintext: "super-secret"})
+
+	loader := NewLoader("describe-redaction", nil)
+	loader.Register(&mapSource{name: "mem", settings: map[string]interface{}{
+		"password": "!enc:test-describe:aWdub3JlZA==",
+		"name":     "svc",
+	}})
+
+	var cfg struct {
+		Password string `mapstructure:"password"`
+		Name     string `mapstructure:"name"`
+	}
+	if err := loader.Load(&cfg); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if cfg.Password != "super-secret" {
+		t.Fatalf("expected the live config to hold the decrypted secret, got %q", cfg.Password)
+	}
+
+	desc := loader.Describe()
+	if got := desc.Values["password"]; got != redactedSecretValue {
+		t.Fatalf("expected Describe to redact the decrypted secret, got %v", got)
+	}
+	if got := desc.Values["name"]; got != "svc" {
+		t.Fatalf("expected an unrelated key to pass through Describe unchanged, got %v", got)
+	}
+}
@@ -1,76 +1,346 @@
 package config
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
+	"reflect"
 	"strings"
+	"sync"
 
-	"github.com/fsnotify/fsnotify"
 	"github.com/spf13/viper"
 	"go.uber.org/zap"
 )
 
-// LoadConfig loads configuration from files into the provided target struct.
-func LoadConfig(configName string, target interface{}, log *zap.Logger) error {
-	v := viper.New()
-	v.SetConfigName(strings.ToLower(configName))
-	v.SetConfigType("yaml")
-
-	// Add common configuration paths
-	v.AddConfigPath(".")
-	v.AddConfigPath("./config")
-
-	// Load environment-specific configuration files with precedence
-	envConfigs := []string{"GOLETAN_PROD_CONFIG", "GOLETAN_STAGE_CONFIG", "GOLETAN_LOCAL_CONFIG"}
-	for _, envVar := range envConfigs {
-		envValue := os.Getenv(envVar)
-		if envValue != "" {
-			configPath := fmt.Sprintf("./config/%s.yaml", envValue)
-			loadConfigFiles([]string{configPath}, v, log)
+// Loader merges a set of ordered Sources into a single in-memory view and
+// unmarshals that view into a target struct. Sources are merged in
+// registration order, with later sources overriding earlier ones, so callers
+// typically register files first, then environment variables, then
+// command-line flags, then any remote source that should win above all.
+type Loader struct {
+	name    string
+	log     *zap.Logger
+	sources []Source
+
+	// loadMu serializes whole Load calls (merge, decrypt, unmarshal,
+	// validate, and the final swap into target), not just the snapshot
+	// swap. LoadConfig starts one watchFile goroutine per resolved config
+	// file, so near-simultaneous edits to two overlay files can call Load
+	// concurrently; without this, both build a fresh value via reflection
+	// against the same target with no guarantee the later write reflects
+	// the more recent merge.
+	loadMu    sync.Mutex
+	watchOnce sync.Once
+
+	stateMu         sync.Mutex
+	handles         map[string]reloadableHandle
+	lastSnapshot    map[string]interface{}
+	lastSources     []string
+	lastAttribution map[string]string
+	lastDecrypted   map[string]bool
+}
+
+// NewLoader creates an empty Loader for the named configuration. name is
+// used to key LastReloadError; unlike LoadConfig, Load does not write the
+// config cache (StoreConfigInCache). Use Register to add Sources before
+// calling Load.
+func NewLoader(name string, log *zap.Logger) *Loader {
+	return &Loader{name: name, log: log}
+}
+
+// Register appends sources to the merge pipeline, in the order given. Any
+// source that implements WatchableSource starts emitting reload events as
+// soon as the first Load call has run, so a remote KV or Kubernetes
+// ConfigMap source gets the same automatic reload a watched file gets,
+// without the caller polling Load itself. Watching is set up once, on the
+// first Load, so a source registered after that is never watched.
+func (l *Loader) Register(sources ...Source) *Loader {
+	l.sources = append(l.sources, sources...)
+	return l
+}
+
+// startWatching subscribes to every registered WatchableSource and spawns a
+// goroutine that re-runs Load against target whenever any of them reports a
+// change. It runs at most once per Loader, triggered by the first Load call,
+// since that's the first point a target is available to reload into.
+func (l *Loader) startWatching(target interface{}) {
+	var watchable bool
+	for _, s := range l.sources {
+		if _, ok := s.(WatchableSource); ok {
+			watchable = true
+			break
+		}
+	}
+	if !watchable {
+		return
+	}
+
+	// The consumer goroutine must be running before any source's Watch is
+	// called, since Watch must not block and a source may fire as soon as
+	// it's wired up.
+	events := make(chan Event)
+	go func() {
+		for ev := range events {
+			if err := l.Load(target); err != nil && l.log != nil {
+				l.log.Error("Failed to reload configuration after source change",
+					zap.String("source", ev.Source), zap.Error(err))
+			}
+		}
+	}()
+
+	for _, s := range l.sources {
+		ws, ok := s.(WatchableSource)
+		if !ok {
+			continue
+		}
+		if err := ws.Watch(events); err != nil && l.log != nil {
+			l.log.Warn("Failed to start watching configuration source",
+				zap.String("source", s.Name()), zap.Error(err))
+		}
+	}
+}
+
+// Load reads every registered source, merges them in order, and unmarshals
+// the result into a fresh instance of target's type. Only if that unmarshal
+// and validation succeed does Load swap the new value into target, so a
+// malformed source can never leave target partially overwritten. On failure,
+// target is left untouched and the error is recorded for LastReloadError. A
+// source that fails to read (e.g. a required profile file that is missing)
+// fails the whole Load rather than being silently skipped.
+//
+// A secret that fails to decrypt does not, by itself, fail the Load: its
+// key is forgiven against a `validate:"required"` check it would otherwise
+// trip (see validateConfig), and the decrypt failure is reported via
+// LastReloadError instead. Validation failures unrelated to a decrypt
+// failure still fail the Load as normal, with any decrypt errors joined in
+// so they're never silently dropped behind an unrelated one.
+func (l *Loader) Load(target interface{}) error {
+	l.loadMu.Lock()
+	defer l.loadMu.Unlock()
+
+	l.watchOnce.Do(func() { l.startWatching(target) })
+
+	merged := map[string]interface{}{}
+	attribution := map[string]string{}
+	var contributed []string
+	for _, s := range l.sources {
+		settings, err := s.Read()
+		if err != nil {
+			err = fmt.Errorf("source %s: %w", s.Name(), err)
+			if l.log != nil {
+				l.log.Error("Failed to read configuration source", zap.String("source", s.Name()), zap.Error(err))
+			}
+			setLastReloadError(l.name, err)
+			return err
 		}
+		if len(settings) > 0 {
+			contributed = append(contributed, s.Name())
+		}
+		mergeMapsAttributed(merged, settings, "", attribution, s.Name())
 	}
 
-	// Load common configuration files
-	loadConfigFiles([]string{
-		"./config/override.yaml",
-		"./config/tests.yaml",
-	}, v, log)
+	decryptErrs, decrypted := decryptSecrets(context.Background(), merged)
+	for _, err := range decryptErrs {
+		if l.log != nil {
+			l.log.Error("Failed to decrypt configuration secret", zap.Error(err))
+		}
+	}
+	failedSecretKeys := decryptFailedKeys(decryptErrs)
+
+	v := viper.New()
+	if err := v.MergeConfigMap(merged); err != nil {
+		err = fmt.Errorf("failed to merge configuration sources: %w", err)
+		setLastReloadError(l.name, err)
+		return err
+	}
+
+	rv := reflect.ValueOf(target)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		err := fmt.Errorf("config target must be a non-nil pointer, got %T", target)
+		setLastReloadError(l.name, err)
+		return err
+	}
 
-	// Read the configuration file
-	if err := v.ReadInConfig(); err != nil {
-		if log != nil {
-			log.Error("Failed to read configuration file", zap.Error(err))
+	fresh := reflect.New(rv.Elem().Type())
+	if err := v.Unmarshal(fresh.Interface()); err != nil {
+		if l.log != nil {
+			l.log.Error("Failed to parse configuration", zap.Error(err))
 		}
-		return fmt.Errorf("failed to read configuration file: %w", err)
+		err = fmt.Errorf("failed to parse configuration: %w", err)
+		setLastReloadError(l.name, err)
+		return err
 	}
 
-	// Unmarshal the configuration into the target struct
-	if err := v.Unmarshal(target); err != nil {
-		if log != nil {
-			log.Error("Failed to parse configuration", zap.Error(err))
+	if err := applyDefaults(fresh); err != nil {
+		if l.log != nil {
+			l.log.Error("Failed to apply configuration defaults", zap.Error(err))
 		}
-		return fmt.Errorf("failed to parse configuration: %w", err)
+		setLastReloadError(l.name, err)
+		return err
 	}
 
-	// Set up hot-reloading
-	v.OnConfigChange(func(e fsnotify.Event) {
-		if log != nil {
-			log.Info("Configuration file changed", zap.String("file", e.Name))
+	if err := validateConfig(fresh.Interface(), failedSecretKeys); err != nil {
+		if l.log != nil {
+			l.log.Error("Configuration failed validation", zap.Error(err))
 		}
+		err = joinDecryptErrs(err, decryptErrs)
+		setLastReloadError(l.name, err)
+		return err
+	}
 
-		if err := v.Unmarshal(target); err != nil {
-			if log != nil {
-				log.Error("Failed to reload configuration", zap.Error(err))
+	if validator, ok := fresh.Interface().(Validator); ok {
+		if err := validator.Validate(); err != nil {
+			if l.log != nil {
+				l.log.Error("Configuration failed validation", zap.Error(err))
 			}
-		} else {
-			if log != nil {
-				log.Info("Configuration reloaded successfully")
+			err = fmt.Errorf("configuration validation failed: %w", err)
+			err = joinDecryptErrs(err, decryptErrs)
+			setLastReloadError(l.name, err)
+			return err
+		}
+	}
+
+	rv.Elem().Set(fresh.Elem())
+	if len(decryptErrs) > 0 {
+		setLastReloadError(l.name, fmt.Errorf("%d configuration secret(s) failed to decrypt: %w", len(decryptErrs), errors.Join(decryptErrs...)))
+	} else {
+		setLastReloadError(l.name, nil)
+	}
+
+	l.stateMu.Lock()
+	l.lastSnapshot = merged
+	l.lastSources = contributed
+	l.lastAttribution = attribution
+	l.lastDecrypted = decrypted
+	for _, h := range l.handles {
+		h.refresh(merged)
+	}
+	l.stateMu.Unlock()
+
+	return nil
+}
+
+// joinDecryptErrs folds decryptErrs into err so a validation failure
+// unrelated to any decrypt failure never hides the decrypt failure(s)
+// reported separately to the log and, on success, to LastReloadError.
+func joinDecryptErrs(err error, decryptErrs []error) error {
+	if len(decryptErrs) == 0 {
+		return err
+	}
+	return errors.Join(append([]error{err}, decryptErrs...)...)
+}
+
+// redactedSecretValue replaces the decrypted plaintext of a key that came
+// from an "!enc:<scheme>:<ciphertext>" value in Describe().Values, since
+// Describe is a debugging/introspection surface that may be read by more
+// than just the process holding the config in memory.
+const redactedSecretValue = "***redacted***"
+
+// Describe reports the outcome of the most recent successful Load: which
+// sources actually contributed data, the fully merged effective view (as
+// dotted keys), and which source supplied each key. It exists for the "why
+// is this value X in staging?" debugging session, an in-process analogue of
+// a /config/environment introspection endpoint.
+type Describe struct {
+	// Sources lists the sources that contributed data, in merge order.
+	Sources []string
+	// Values is the final effective configuration, flattened to dotted keys.
+	// A key that held an encrypted secret is reported as redactedSecretValue
+	// rather than its decrypted plaintext.
+	Values map[string]interface{}
+	// Attribution maps each dotted key in Values to the Name() of the
+	// source that supplied it.
+	Attribution map[string]string
+}
+
+// Describe returns a snapshot of the most recent successful Load.
+func (l *Loader) Describe() Describe {
+	l.stateMu.Lock()
+	defer l.stateMu.Unlock()
+
+	values := map[string]interface{}{}
+	flattenTree(l.lastSnapshot, "", values)
+	for key := range l.lastDecrypted {
+		if _, ok := values[key]; ok {
+			values[key] = redactedSecretValue
+		}
+	}
+
+	attribution := make(map[string]string, len(l.lastAttribution))
+	for k, v := range l.lastAttribution {
+		attribution[k] = v
+	}
+
+	return Describe{
+		Sources:     append([]string(nil), l.lastSources...),
+		Values:      values,
+		Attribution: attribution,
+	}
+}
+
+// LoadOption customizes LoadConfig's behavior.
+type LoadOption func(*loadOptions)
+
+type loadOptions struct {
+	requireProfileEnvFile bool
+}
+
+// RequireProfileEnvFile makes LoadConfig fail if the environment-specific
+// profile file (config/<GOLETAN_ENV>.yaml) does not exist, instead of
+// silently falling back to config/base.yaml alone. Use this in deployments
+// where a missing profile almost certainly means a bad rollout rather than
+// an intentionally minimal environment.
+func RequireProfileEnvFile() LoadOption {
+	return func(o *loadOptions) { o.requireProfileEnvFile = true }
+}
+
+// LoadConfig loads configuration from files into the provided target struct.
+// It builds a default Loader pipeline modeled on the package's historical
+// behavior: "./<configName>.yaml" and then "./config/<configName>.yaml"
+// (configName lowercased; both are merged when present), overlaid
+// with the Profile selected by GOLETAN_ENV (see Profile) and finally
+// "./config/override.yaml" and "./config/tests.yaml". Callers that need
+// other sources (remote KV, flags, Kubernetes ConfigMaps, ...) should build
+// their own Loader instead, wrapping any FileSource in NewWatchedFileSource
+// to keep the same automatic reload LoadConfig gives its own files.
+func LoadConfig(configName string, target interface{}, log *zap.Logger, opts ...LoadOption) error {
+	var options loadOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	profile := ProfileFromEnv()
+	profile.RequireEnvFile = options.requireProfileEnvFile
+
+	loader := NewLoader(configName, log)
+	loader.Register(defaultSources(configName, profile)...)
+
+	if err := loader.Load(target); err != nil {
+		return err
+	}
+
+	// Set up hot-reloading on the name-specific files and the profile's
+	// base/env/local files that actually exist, since the effective
+	// configuration is spread across them. override.yaml and tests.yaml are
+	// merged but not watched.
+	for _, path := range resolveWatchPaths(configName, profile) {
+		path := path
+		if _, err := watchFile(path, log, func() {
+			if err := loader.Load(target); err != nil {
+				if log != nil {
+					log.Error("Failed to reload configuration", zap.Error(err))
+				}
+			} else {
+				if log != nil {
+					log.Info("Configuration reloaded successfully")
+				}
+				StoreConfigInCache(configName, target)
 			}
-			// Update the cache after reloading
-			StoreConfigInCache(configName, target)
+		}); err != nil && log != nil {
+			log.Warn("Failed to start configuration watcher", zap.String("file", path), zap.Error(err))
 		}
-	})
-	v.WatchConfig()
+	}
 
 	if log != nil {
 		log.Info("Configuration loaded successfully")
@@ -82,16 +352,39 @@ func LoadConfig(configName string, target interface{}, log *zap.Logger) error {
 	return nil
 }
 
-// loadConfigFiles attempts to load a list of configuration files in order, with precedence.
-func loadConfigFiles(configFiles []string, v *viper.Viper, log *zap.Logger) {
-	for _, configPath := range configFiles {
-		if _, err := os.Stat(configPath); err == nil {
-			v.SetConfigFile(configPath)
-			if err := v.MergeInConfig(); err != nil {
-				if log != nil {
-					log.Warn("Failed to merge configuration file", zap.String("file", configPath), zap.Error(err))
-				}
-			}
+// defaultSources builds the ordered pipeline LoadConfig has always used:
+// the named config file, then the selected profile's files, then the common
+// override files.
+func defaultSources(configName string, profile Profile) []Source {
+	sources := []Source{
+		NewFileSource(fmt.Sprintf("./%s.yaml", strings.ToLower(configName))),
+		NewFileSource(fmt.Sprintf("./config/%s.yaml", strings.ToLower(configName))),
+	}
+
+	sources = append(sources, profile.Sources()...)
+
+	sources = append(sources,
+		NewFileSource("./config/override.yaml"),
+		NewFileSource("./config/tests.yaml"),
+	)
+
+	return sources
+}
+
+// resolveWatchPaths returns every existing file among the primary
+// name-specific candidates and the profile's files, so a hot-reload fires
+// no matter which one an operator edits.
+func resolveWatchPaths(configName string, profile Profile) []string {
+	candidates := append([]string{
+		fmt.Sprintf("./%s.yaml", strings.ToLower(configName)),
+		fmt.Sprintf("./config/%s.yaml", strings.ToLower(configName)),
+	}, profile.Files()...)
+
+	var existing []string
+	for _, candidate := range candidates {
+		if _, err := os.Stat(candidate); err == nil {
+			existing = append(existing, candidate)
 		}
 	}
-}
\ No newline at end of file
+	return existing
+}
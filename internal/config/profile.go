@@ -0,0 +1,63 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Profile resolves the ordered set of config files for one environment. It
+// replaces the old GOLETAN_PROD_CONFIG / GOLETAN_STAGE_CONFIG /
+// GOLETAN_LOCAL_CONFIG env vars, which were order-sensitive and silently
+// ignored missing files. A single GOLETAN_ENV selects the profile.
+//
+// Override precedence, lowest to highest:
+//
+//  1. config/base.yaml        - always loaded
+//  2. config/<env>.yaml       - the selected environment's overrides
+//  3. config/<env>.local.yaml - optional machine-local overrides, not committed
+type Profile struct {
+	// Env is the environment name, e.g. "prod", "stage", "local".
+	Env string
+	// RequireEnvFile fails Load if config/<env>.yaml does not exist, instead
+	// of silently running on config/base.yaml alone.
+	RequireEnvFile bool
+}
+
+// ProfileFromEnv builds a Profile from GOLETAN_ENV, defaulting to "local" so
+// a bare checkout with no environment configured still runs.
+func ProfileFromEnv() Profile {
+	env := strings.ToLower(os.Getenv("GOLETAN_ENV"))
+	if env == "" {
+		env = "local"
+	}
+	return Profile{Env: env}
+}
+
+// Files returns the profile's config files in override precedence, lowest
+// first.
+func (p Profile) Files() []string {
+	return []string{
+		"./config/base.yaml",
+		fmt.Sprintf("./config/%s.yaml", p.Env),
+		fmt.Sprintf("./config/%s.local.yaml", p.Env),
+	}
+}
+
+// Sources returns Files() as ordered Sources ready to Register on a Loader.
+// The environment file becomes a NewRequiredFileSource when RequireEnvFile
+// is set; base.yaml and the local overlay are always optional.
+func (p Profile) Sources() []Source {
+	files := p.Files()
+
+	newEnvSource := NewFileSource
+	if p.RequireEnvFile {
+		newEnvSource = NewRequiredFileSource
+	}
+
+	return []Source{
+		NewFileSource(files[0]),
+		newEnvSource(files[1]),
+		NewFileSource(files[2]),
+	}
+}
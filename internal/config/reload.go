@@ -0,0 +1,41 @@
+package config
+
+import "sync"
+
+// Validator is implemented by configuration structs that need to assert
+// invariants beyond what Viper's Unmarshal can express (e.g. cross-field
+// constraints such as "if TLS.Enabled then TLS.CertFile must be set"). If a
+// target passed to Loader.Load implements Validator, Validate() is called on
+// the freshly unmarshaled instance before it replaces the live config.
+type Validator interface {
+	Validate() error
+}
+
+var (
+	lastReloadErrMu sync.RWMutex
+	lastReloadErr   = map[string]error{}
+)
+
+// setLastReloadError records the outcome of the most recent reload attempt
+// for configName. A nil err clears any previously recorded failure.
+func setLastReloadError(configName string, err error) {
+	lastReloadErrMu.Lock()
+	defer lastReloadErrMu.Unlock()
+	if err == nil {
+		delete(lastReloadErr, configName)
+		return
+	}
+	lastReloadErr[configName] = err
+}
+
+// LastReloadError returns the error from the most recent failed reload of
+// configName, or nil if the last reload (or the initial load) succeeded. A
+// load that succeeded with one or more secrets failing to decrypt still
+// reports those decrypt failures here.
+// Callers such as health checks can poll this to detect a config that is
+// still running on stale data because an edit failed validation.
+func LastReloadError(configName string) error {
+	lastReloadErrMu.RLock()
+	defer lastReloadErrMu.RUnlock()
+	return lastReloadErr[configName]
+}
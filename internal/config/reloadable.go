@@ -0,0 +1,244 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Reloadable is a typed handle onto a single dotted config key that always
+// reflects the latest successfully loaded value. Obtain one from a Loader
+// via GetReloadableString/Int/Bool/Duration/StringSlice, call Load() to read
+// the current value safely from any goroutine, and OnChange to be notified
+// when a reload actually changes the key. This is the safe alternative to
+// reading fields off a shared target struct from multiple goroutines.
+type Reloadable[T any] struct {
+	mu        sync.RWMutex
+	key       string
+	def       T
+	value     T
+	listeners []func(old, new T)
+}
+
+// Load returns the current value of the key.
+func (r *Reloadable[T]) Load() T {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.value
+}
+
+// OnChange registers fn to be called whenever a reload changes the key's
+// value. fn is not called for the initial value, only for changes.
+func (r *Reloadable[T]) OnChange(fn func(old, new T)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.listeners = append(r.listeners, fn)
+}
+
+func (r *Reloadable[T]) set(newVal T) {
+	r.mu.Lock()
+	old := r.value
+	changed := !reflect.DeepEqual(old, newVal)
+	r.value = newVal
+	var listeners []func(old, new T)
+	if changed {
+		listeners = append(listeners, r.listeners...)
+	}
+	r.mu.Unlock()
+
+	for _, fn := range listeners {
+		fn(old, newVal)
+	}
+}
+
+// reloadableHandle is the type-erased side of a Reloadable[T] that the
+// Loader can hold in a single map regardless of T.
+type reloadableHandle interface {
+	typeName() string
+	refresh(snapshot map[string]interface{})
+}
+
+type reloadableEntry[T any] struct {
+	handle  *Reloadable[T]
+	convert func(interface{}) (T, bool)
+}
+
+func (e *reloadableEntry[T]) typeName() string {
+	return fmt.Sprintf("%T", e.handle.def)
+}
+
+func (e *reloadableEntry[T]) refresh(snapshot map[string]interface{}) {
+	raw, present := lookupKey(snapshot, e.handle.key)
+	if !present {
+		e.handle.set(e.handle.def)
+		return
+	}
+	if v, ok := e.convert(raw); ok {
+		e.handle.set(v)
+	}
+}
+
+// getOrRegisterReloadable returns the existing handle for key if one was
+// already registered on l with a matching type, or creates one.
+// A key re-registered with a different default is logged as likely
+// configuration drift, but the original handle stays authoritative and keeps
+// receiving every future refresh.
+//
+// A key re-registered with a different type can't return that original
+// handle at all: the caller expects a *Reloadable[T] for its own T, not
+// whatever type got there first. Rather than repeat the bug this replaced
+// (silently overwriting l.handles[key], which froze the first caller's
+// handle at its last value forever with no signal it would ever receive),
+// the first registration keeps sole ownership of key in l.handles, and this
+// call gets back an unregistered handle: seeded once from the current
+// snapshot, but never wired up to refresh again. logMisuse still fires, so
+// the drift is visible at the call site that caused it instead of at the
+// unrelated caller who registered first and did nothing wrong.
+func getOrRegisterReloadable[T any](l *Loader, key string, def T, convert func(interface{}) (T, bool)) *Reloadable[T] {
+	l.stateMu.Lock()
+	defer l.stateMu.Unlock()
+
+	if l.handles == nil {
+		l.handles = map[string]reloadableHandle{}
+	}
+
+	if existing, ok := l.handles[key]; ok {
+		if typed, ok := existing.(*reloadableEntry[T]); ok {
+			if !reflect.DeepEqual(typed.handle.def, def) {
+				logMisuse(l, key, typed.typeName(), fmt.Sprintf("%T", def), "default")
+			}
+			return typed.handle
+		}
+		logMisuse(l, key, existing.typeName(), fmt.Sprintf("%T", def), "type")
+		return newReloadableHandle(l, key, def, convert)
+	}
+
+	handle := newReloadableHandle(l, key, def, convert)
+	l.handles[key] = &reloadableEntry[T]{handle: handle, convert: convert}
+	return handle
+}
+
+// newReloadableHandle builds a handle for key, seeded from l.lastSnapshot if
+// it's already present there, without registering it in l.handles.
+func newReloadableHandle[T any](l *Loader, key string, def T, convert func(interface{}) (T, bool)) *Reloadable[T] {
+	handle := &Reloadable[T]{key: key, def: def, value: def}
+	if raw, present := lookupKey(l.lastSnapshot, key); present {
+		if v, ok := convert(raw); ok {
+			handle.value = v
+		}
+	}
+	return handle
+}
+
+func logMisuse(l *Loader, key, existing, requested, what string) {
+	if l.log == nil {
+		return
+	}
+	l.log.Warn("Reloadable key re-registered with a different "+what+"; likely configuration drift",
+		zap.String("key", key), zap.String("existing", existing), zap.String("requested", requested))
+}
+
+// GetReloadableString returns a handle onto key that always reflects the
+// latest value as a string, or def if the key is absent.
+func (l *Loader) GetReloadableString(key, def string) *Reloadable[string] {
+	return getOrRegisterReloadable(l, key, def, func(raw interface{}) (string, bool) {
+		s, ok := raw.(string)
+		return s, ok
+	})
+}
+
+// GetReloadableInt returns a handle onto key that always reflects the latest
+// value as an int, or def if the key is absent.
+func (l *Loader) GetReloadableInt(key string, def int) *Reloadable[int] {
+	return getOrRegisterReloadable(l, key, def, func(raw interface{}) (int, bool) {
+		switch v := raw.(type) {
+		case int:
+			return v, true
+		case int64:
+			return int(v), true
+		case float64:
+			return int(v), true
+		default:
+			return 0, false
+		}
+	})
+}
+
+// GetReloadableBool returns a handle onto key that always reflects the
+// latest value as a bool, or def if the key is absent.
+func (l *Loader) GetReloadableBool(key string, def bool) *Reloadable[bool] {
+	return getOrRegisterReloadable(l, key, def, func(raw interface{}) (bool, bool) {
+		b, ok := raw.(bool)
+		return b, ok
+	})
+}
+
+// GetReloadableDuration returns a handle onto key that always reflects the
+// latest value as a time.Duration, or def if the key is absent. String
+// values are parsed with time.ParseDuration (e.g. "30s"); numeric values are
+// treated as nanoseconds, matching time.Duration's underlying type.
+func (l *Loader) GetReloadableDuration(key string, def time.Duration) *Reloadable[time.Duration] {
+	return getOrRegisterReloadable(l, key, def, func(raw interface{}) (time.Duration, bool) {
+		switch v := raw.(type) {
+		case string:
+			d, err := time.ParseDuration(v)
+			if err != nil {
+				return 0, false
+			}
+			return d, true
+		case int:
+			return time.Duration(v), true
+		case int64:
+			return time.Duration(v), true
+		case float64:
+			return time.Duration(v), true
+		default:
+			return 0, false
+		}
+	})
+}
+
+// GetReloadableStringSlice returns a handle onto key that always reflects
+// the latest value as a []string, or def if the key is absent.
+func (l *Loader) GetReloadableStringSlice(key string, def []string) *Reloadable[[]string] {
+	return getOrRegisterReloadable(l, key, def, func(raw interface{}) ([]string, bool) {
+		switch v := raw.(type) {
+		case []string:
+			return v, true
+		case []interface{}:
+			out := make([]string, 0, len(v))
+			for _, item := range v {
+				s, ok := item.(string)
+				if !ok {
+					return nil, false
+				}
+				out = append(out, s)
+			}
+			return out, true
+		default:
+			return nil, false
+		}
+	})
+}
+
+// lookupKey walks tree following the dotted segments of key and returns the
+// value found there, if any.
+func lookupKey(tree map[string]interface{}, key string) (interface{}, bool) {
+	var node interface{} = tree
+	for _, part := range strings.Split(key, ".") {
+		m, ok := node.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		val, ok := m[part]
+		if !ok {
+			return nil, false
+		}
+		node = val
+	}
+	return node, true
+}
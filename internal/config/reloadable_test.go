@@ -0,0 +1,112 @@
+package config
+
+import "testing"
+
+// TestReloadable_OnChange_FiresOnlyWhenValueChanges confirms OnChange is
+// not called for the value a Reloadable already holds after registration,
+// only for reloads that actually change it, and that it sees the old and
+// new values in the order Load's reload produced them.
+func TestReloadable_OnChange_FiresOnlyWhenValueChanges(t *testing.T) {
+	loader := NewLoader("reloadable", nil)
+	source := &mapSource{name: "mem", settings: map[string]interface{}{"level": "info"}}
+	loader.Register(source)
+
+	var target loadTarget
+	if err := loader.Load(&target); err != nil {
+		t.Fatalf("initial Load: %v", err)
+	}
+
+	level := loader.GetReloadableString("level", "warn")
+	if got := level.Load(); got != "info" {
+		t.Fatalf("expected initial value from the loaded config, got %q", got)
+	}
+
+	var calls []string
+	level.OnChange(func(old, new string) {
+		calls = append(calls, old+"->"+new)
+	})
+
+	// Reload with the same value: OnChange must not fire.
+	if err := loader.Load(&target); err != nil {
+		t.Fatalf("no-op Load: %v", err)
+	}
+	if len(calls) != 0 {
+		t.Fatalf("expected no OnChange calls for an unchanged value, got %v", calls)
+	}
+
+	source.setSettings(map[string]interface{}{"level": "debug"})
+	if err := loader.Load(&target); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if len(calls) != 1 || calls[0] != "info->debug" {
+		t.Fatalf("expected exactly one OnChange call reporting info->debug, got %v", calls)
+	}
+	if got := level.Load(); got != "debug" {
+		t.Fatalf("expected Load() to reflect the new value, got %q", got)
+	}
+}
+
+// TestReloadable_MissingKeyFallsBackToDefault confirms a key absent from
+// every source resolves to the default passed to GetReloadableString, and
+// that a later reload which removes the key from all sources falls back to
+// the default again rather than sticking on the last seen value.
+func TestReloadable_MissingKeyFallsBackToDefault(t *testing.T) {
+	loader := NewLoader("reloadable-default", nil)
+	source := &mapSource{name: "mem", settings: map[string]interface{}{"level": "info"}}
+	loader.Register(source)
+
+	var target loadTarget
+	if err := loader.Load(&target); err != nil {
+		t.Fatalf("initial Load: %v", err)
+	}
+
+	level := loader.GetReloadableString("level", "warn")
+	if got := level.Load(); got != "info" {
+		t.Fatalf("expected initial value, got %q", got)
+	}
+
+	source.setSettings(map[string]interface{}{})
+	if err := loader.Load(&target); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if got := level.Load(); got != "warn" {
+		t.Fatalf("expected fallback to default once the key disappears, got %q", got)
+	}
+}
+
+// TestReloadable_TypeCollisionKeepsFirstHandleAuthoritative confirms that
+// re-registering a key with a different T doesn't detach the first caller's
+// handle from future refreshes: only the second, colliding caller gets a
+// handle that never updates again.
+func TestReloadable_TypeCollisionKeepsFirstHandleAuthoritative(t *testing.T) {
+	loader := NewLoader("reloadable-collision", nil)
+	source := &mapSource{name: "mem", settings: map[string]interface{}{"level": "info"}}
+	loader.Register(source)
+
+	var target loadTarget
+	if err := loader.Load(&target); err != nil {
+		t.Fatalf("initial Load: %v", err)
+	}
+
+	str := loader.GetReloadableString("level", "warn")
+	if got := str.Load(); got != "info" {
+		t.Fatalf("expected initial string value, got %q", got)
+	}
+
+	num := loader.GetReloadableInt("level", 0)
+	if got := num.Load(); got != 0 {
+		t.Fatalf("expected the colliding handle to fall back to its own default, got %d", got)
+	}
+
+	source.setSettings(map[string]interface{}{"level": "debug"})
+	if err := loader.Load(&target); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+
+	if got := str.Load(); got != "debug" {
+		t.Fatalf("expected the first handle to keep receiving refreshes, got %q", got)
+	}
+	if got := num.Load(); got != 0 {
+		t.Fatalf("expected the colliding handle to stay frozen at its own default, got %d", got)
+	}
+}
@@ -0,0 +1,53 @@
+//go:build age
+
+package config
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"filippo.io/age"
+)
+
+// AgeIdentityProvider decrypts "!enc:<scheme>:<base64>" secrets encrypted
+// with the standard age format (e.g. `age -r <recipient>`), using an
+// identity file in the format age-keygen writes. Unlike LocalKeyProvider's
+// ad hoc AES-256-GCM scheme, ciphertext is wire-compatible with the real
+// age tool, so it can be produced and inspected with age/age-keygen
+// directly instead of a bespoke encryptor. It's isolated behind the "age"
+// build tag so importing this package doesn't force every consumer to
+// vendor filippo.io/age; only a binary built with `-tags age` links it in.
+type AgeIdentityProvider struct {
+	identities []age.Identity
+}
+
+// NewAgeIdentityProvider loads the identities (private keys) from
+// identityFile and returns a ready SecretProvider.
+func NewAgeIdentityProvider(identityFile string) (*AgeIdentityProvider, error) {
+	f, err := os.Open(identityFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open age identity file %s: %w", identityFile, err)
+	}
+	defer f.Close()
+
+	identities, err := age.ParseIdentities(f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse age identity file %s: %w", identityFile, err)
+	}
+	return &AgeIdentityProvider{identities: identities}, nil
+}
+
+func (p *AgeIdentityProvider) Decrypt(_ context.Context, ciphertext []byte) ([]byte, error) {
+	r, err := age.Decrypt(bytes.NewReader(ciphertext), p.identities...)
+	if err != nil {
+		return nil, fmt.Errorf("age decrypt: %w", err)
+	}
+	plain, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("age decrypt: %w", err)
+	}
+	return plain, nil
+}
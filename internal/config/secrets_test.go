@@ -0,0 +1,74 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+type brokenSecretProvider struct{}
+
+func (brokenSecretProvider) Decrypt(context.Context, []byte) ([]byte, error) {
+	return nil, fmt.Errorf("boom")
+}
+
+// TestLoader_Load_DecryptFailureDoesNotBlockRequiredField confirms a secret
+// that fails to decrypt, and so unmarshals to its Go zero value, does not
+// fail `validate:"required"` on that same field and abort the Load - the
+// decrypt failure is reported via LastReloadError instead, and unrelated
+// fields still load normally.
+func TestLoader_Load_DecryptFailureDoesNotBlockRequiredField(t *testing.T) {
+	type target struct {
+		Password string `mapstructure:"password" validate:"required"`
+		Name     string `mapstructure:"name" validate:"required"`
+	}
+
+	RegisterSecretProvider("test-secrets-broken", brokenSecretProvider{})
+
+	loader := NewLoader("decrypt-required", nil)
+	loader.Register(&mapSource{name: "mem", settings: map[string]interface{}{
+		"password": "!enc:test-secrets-broken:Zm9v",
+		"name":     "svc",
+	}})
+
+	var cfg target
+	if err := loader.Load(&cfg); err != nil {
+		t.Fatalf("Load should succeed despite the decrypt failure, got: %v", err)
+	}
+	if cfg.Name != "svc" {
+		t.Fatalf("expected unrelated field to load normally, got %q", cfg.Name)
+	}
+	if reloadErr := LastReloadError("decrypt-required"); reloadErr == nil {
+		t.Fatal("expected LastReloadError to report the decrypt failure")
+	}
+}
+
+// TestLoader_Load_UnrelatedValidationFailureStillFailsWithDecryptErrJoined
+// confirms that when validation fails for a reason other than a
+// decrypt-zeroed required field, the Load still fails - and the decrypt
+// error is folded into the returned/recorded error rather than dropped.
+func TestLoader_Load_UnrelatedValidationFailureStillFailsWithDecryptErrJoined(t *testing.T) {
+	type target struct {
+		Password string `mapstructure:"password" validate:"required"`
+		Name     string `mapstructure:"name" validate:"required"`
+	}
+
+	RegisterSecretProvider("test-secrets-broken", brokenSecretProvider{})
+
+	loader := NewLoader("decrypt-unrelated-failure", nil)
+	loader.Register(&mapSource{name: "mem", settings: map[string]interface{}{
+		"password": "!enc:test-secrets-broken:Zm9v",
+		// "name" deliberately omitted, so its own "required" check fails
+		// independently of the decrypt failure above.
+	}})
+
+	var cfg target
+	err := loader.Load(&cfg)
+	if err == nil {
+		t.Fatal("expected Load to fail due to the unrelated missing required field")
+	}
+	if !strings.Contains(err.Error(), "boom") {
+		t.Fatalf("expected the decrypt failure to be joined into the returned error, got: %v", err)
+	}
+}
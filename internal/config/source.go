@@ -0,0 +1,207 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// Event is emitted by a WatchableSource when the data it reads may have changed.
+// The Loader reacts by re-running the merge pipeline and re-unmarshaling target.
+type Event struct {
+	// Source is the Name() of the source that fired the event.
+	Source string
+}
+
+// Source is a single configuration input. The Loader merges sources in
+// registration order, with later sources overriding earlier ones on
+// conflicting keys. Implementations should be cheap to call repeatedly,
+// since Read is invoked on every load and every reload.
+type Source interface {
+	// Name identifies the source for logging and diagnostics.
+	Name() string
+	// Read returns the source's current view of the configuration.
+	Read() (map[string]interface{}, error)
+}
+
+// WatchableSource is implemented by sources that can observe external changes
+// (a file on disk, a remote KV watch stream, ...) and notify the Loader so it
+// can trigger a reload instead of relying on the caller to poll.
+type WatchableSource interface {
+	Source
+	// Watch starts observing for changes and sends an Event on events each
+	// time the underlying data may have changed. Watch returns once the
+	// watch is established; it must not block.
+	Watch(events chan<- Event) error
+}
+
+// FileSource reads a single YAML configuration file. By default, a missing
+// file is not an error: Read returns an empty map so optional overlays
+// (override.yaml, tests.yaml, ...) can be registered unconditionally. Use
+// NewRequiredFileSource for files that must exist, such as a selected
+// environment profile. FileSource alone does not implement WatchableSource;
+// wrap it with NewWatchedFileSource for automatic reload on a custom Loader.
+type FileSource struct {
+	path     string
+	required bool
+}
+
+// NewFileSource creates a Source backed by the YAML file at path. A missing
+// file is treated as empty.
+func NewFileSource(path string) *FileSource {
+	return &FileSource{path: path}
+}
+
+// NewRequiredFileSource creates a Source backed by the YAML file at path
+// whose Read fails if the file does not exist, for fail-fast environments
+// where a missing profile file means the deployment is misconfigured.
+func NewRequiredFileSource(path string) *FileSource {
+	return &FileSource{path: path, required: true}
+}
+
+func (f *FileSource) Name() string {
+	return fmt.Sprintf("file:%s", f.path)
+}
+
+func (f *FileSource) Read() (map[string]interface{}, error) {
+	if _, err := os.Stat(f.path); err != nil {
+		if f.required {
+			return nil, fmt.Errorf("required configuration file %s not found: %w", f.path, err)
+		}
+		return map[string]interface{}{}, nil
+	}
+
+	v := viper.New()
+	v.SetConfigFile(f.path)
+	if err := v.ReadInConfig(); err != nil {
+		return nil, fmt.Errorf("failed to read configuration file %s: %w", f.path, err)
+	}
+	return v.AllSettings(), nil
+}
+
+// EnvSource reads configuration values from environment variables that start
+// with prefix (e.g. "GOLETAN_"). "_" in the variable name is treated as a
+// nesting separator, mirroring Viper's AutomaticEnv / SetEnvKeyReplacer
+// behavior, so GOLETAN_DATABASE_HOST becomes database.host.
+type EnvSource struct {
+	prefix string
+}
+
+// NewEnvSource creates a Source that reads environment variables starting
+// with prefix.
+func NewEnvSource(prefix string) *EnvSource {
+	return &EnvSource{prefix: prefix}
+}
+
+func (e *EnvSource) Name() string {
+	return fmt.Sprintf("env:%s", e.prefix)
+}
+
+func (e *EnvSource) Read() (map[string]interface{}, error) {
+	settings := map[string]interface{}{}
+	for _, kv := range os.Environ() {
+		parts := strings.SplitN(kv, "=", 2)
+		if len(parts) != 2 || !strings.HasPrefix(parts[0], e.prefix) {
+			continue
+		}
+		key := strings.ToLower(strings.TrimPrefix(parts[0], e.prefix))
+		setNestedKey(settings, strings.Split(key, "_"), parts[1])
+	}
+	return settings, nil
+}
+
+// FlagSource reads configuration values already parsed into a flag.FlagSet
+// (or any compatible set of name/value pairs), keyed by dotted flag name
+// (e.g. "database.host").
+type FlagSource struct {
+	values map[string]interface{}
+}
+
+// NewFlagSource creates a Source from a pre-parsed set of flag values.
+// Callers typically populate values by walking flag.FlagSet.Visit after
+// flag.Parse, so only flags explicitly set on the command line override
+// lower-precedence sources.
+func NewFlagSource(values map[string]interface{}) *FlagSource {
+	return &FlagSource{values: values}
+}
+
+func (f *FlagSource) Name() string {
+	return "flags"
+}
+
+func (f *FlagSource) Read() (map[string]interface{}, error) {
+	settings := map[string]interface{}{}
+	for key, value := range f.values {
+		setNestedKey(settings, strings.Split(key, "."), value)
+	}
+	return settings, nil
+}
+
+// setNestedKey assigns value into settings following the dotted path parts,
+// creating intermediate maps as needed.
+func setNestedKey(settings map[string]interface{}, parts []string, value interface{}) {
+	node := settings
+	for i, part := range parts {
+		if i == len(parts)-1 {
+			node[part] = value
+			return
+		}
+		child, ok := node[part].(map[string]interface{})
+		if !ok {
+			child = map[string]interface{}{}
+			node[part] = child
+		}
+		node = child
+	}
+}
+
+// mergeMaps deep-merges src into dst, overriding dst's values on conflict.
+func mergeMaps(dst, src map[string]interface{}) {
+	mergeMapsAttributed(dst, src, "", nil, "")
+}
+
+// mergeMapsAttributed deep-merges src into dst like mergeMaps, and, when
+// attribution is non-nil, records sourceName against the dotted path of
+// every leaf key src sets, so Loader.Describe can report which source
+// supplied the effective value of any key.
+func mergeMapsAttributed(dst, src map[string]interface{}, prefix string, attribution map[string]string, sourceName string) {
+	for key, srcVal := range src {
+		path := key
+		if prefix != "" {
+			path = prefix + "." + key
+		}
+
+		if srcMap, ok := srcVal.(map[string]interface{}); ok {
+			dstMap, ok := dst[key].(map[string]interface{})
+			if !ok {
+				dstMap = map[string]interface{}{}
+				dst[key] = dstMap
+			}
+			mergeMapsAttributed(dstMap, srcMap, path, attribution, sourceName)
+			continue
+		}
+
+		dst[key] = srcVal
+		if attribution != nil {
+			attribution[path] = sourceName
+		}
+	}
+}
+
+// flattenTree turns a nested map into dotted-path -> leaf-value entries,
+// e.g. {"database": {"host": "x"}} becomes {"database.host": "x"}.
+func flattenTree(tree map[string]interface{}, prefix string, out map[string]interface{}) {
+	for key, val := range tree {
+		path := key
+		if prefix != "" {
+			path = prefix + "." + key
+		}
+		if child, ok := val.(map[string]interface{}); ok {
+			flattenTree(child, path, out)
+			continue
+		}
+		out[path] = val
+	}
+}
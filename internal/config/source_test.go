@@ -0,0 +1,66 @@
+package config
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeWatchableSource is a Source/WatchableSource whose Read value can be
+// changed at runtime and whose Watch fires an Event whenever told to, to
+// exercise Loader's automatic-reload wiring without a real remote KV store.
+type fakeWatchableSource struct {
+	mu    sync.Mutex
+	value int
+
+	events chan<- Event
+}
+
+func (s *fakeWatchableSource) Name() string { return "fake-watchable" }
+
+func (s *fakeWatchableSource) Read() (map[string]interface{}, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return map[string]interface{}{"value": s.value}, nil
+}
+
+func (s *fakeWatchableSource) Watch(events chan<- Event) error {
+	s.events = events
+	return nil
+}
+
+func (s *fakeWatchableSource) change(value int) {
+	s.mu.Lock()
+	s.value = value
+	s.mu.Unlock()
+	s.events <- Event{Source: s.Name()}
+}
+
+// TestLoader_Load_ReloadsAutomaticallyOnWatchableSourceEvent confirms a
+// registered WatchableSource (an etcd/consul/Kubernetes-ConfigMap style
+// remote source) triggers a reload on its own, instead of requiring the
+// caller to poll Load.
+func TestLoader_Load_ReloadsAutomaticallyOnWatchableSourceEvent(t *testing.T) {
+	source := &fakeWatchableSource{value: 1}
+	loader := NewLoader("watchable", nil)
+	loader.Register(source)
+
+	var target loadTarget
+	if err := loader.Load(&target); err != nil {
+		t.Fatalf("initial Load: %v", err)
+	}
+	if target.Value != 1 {
+		t.Fatalf("expected initial value 1, got %d", target.Value)
+	}
+
+	source.change(2)
+
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		if v, _ := loader.Describe().Values["value"].(int); v == 2 {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatal("Loader did not reload automatically after a WatchableSource event")
+}
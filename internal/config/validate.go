@@ -0,0 +1,214 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+)
+
+var structValidator = validator.New()
+
+var (
+	namedValidatorsMu sync.RWMutex
+	namedValidators   = map[string]func(interface{}) error{}
+)
+
+// RegisterValidator adds a named cross-field validator that runs against
+// every config struct on load and reload, after struct-tag validation (e.g.
+// "if TLS.Enabled then TLS.CertFile must be set", which a `validate` tag
+// alone cannot express). Re-registering an existing name overwrites it, so
+// callers should pick unique, package-qualified names.
+func RegisterValidator(name string, fn func(interface{}) error) {
+	namedValidatorsMu.Lock()
+	defer namedValidatorsMu.Unlock()
+	namedValidators[name] = fn
+}
+
+// validateConfig applies struct-tag validation (`validate:"required,min=1,url"`,
+// via go-playground/validator) followed by every registered named validator,
+// returning the first error encountered. skipRequiredKeys holds the dotted
+// config keys (the same form GetReloadableString and friends use) whose
+// "required" check should be forgiven, because decryptSecrets already
+// zeroed that field after a decrypt failure reported separately via
+// LastReloadError; failing validation on top of that would bury the real
+// cause behind a generic "required" error.
+func validateConfig(target interface{}, skipRequiredKeys map[string]bool) error {
+	if err := structValidator.Struct(target); err != nil {
+		verrs, ok := err.(validator.ValidationErrors)
+		if !ok {
+			return fmt.Errorf("schema validation failed: %w", err)
+		}
+
+		rootType := reflect.TypeOf(target)
+		var remaining validator.ValidationErrors
+		for _, fe := range verrs {
+			if fe.Tag() == "required" && skipRequiredKeys[configKeyForNamespace(rootType, fe.Namespace())] {
+				continue
+			}
+			remaining = append(remaining, fe)
+		}
+		if len(remaining) > 0 {
+			return fmt.Errorf("schema validation failed: %w", remaining)
+		}
+	}
+
+	namedValidatorsMu.RLock()
+	defer namedValidatorsMu.RUnlock()
+	for name, fn := range namedValidators {
+		if err := fn(target); err != nil {
+			return fmt.Errorf("validator %q failed: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// configKeyForNamespace translates a validator.FieldError's Namespace()
+// (dot-joined Go struct field names rooted at rootType, e.g.
+// "Config.Database.Password") into the dotted config key Viper would use
+// for the same field (e.g. "database.password"), so it can be compared
+// against the keys decryptSecrets reports as failed.
+func configKeyForNamespace(rootType reflect.Type, namespace string) string {
+	if rootType.Kind() == reflect.Ptr {
+		rootType = rootType.Elem()
+	}
+	parts := strings.Split(namespace, ".")
+	if len(parts) < 2 {
+		return ""
+	}
+	key, ok := configKeyPath(rootType, parts[1:])
+	if !ok {
+		return ""
+	}
+	return key
+}
+
+// configKeyPath walks t's fields along goFields (successive Go struct field
+// names) and returns the corresponding dotted config key, following the
+// same mapstructure tag (or lowercased field name) Viper uses to decide a
+// field's key. It returns false if goFields doesn't resolve to a field,
+// which happens for namespace segments validator.ValidationErrors can
+// produce that this package doesn't need to support, such as slice indices.
+func configKeyPath(t reflect.Type, goFields []string) (string, bool) {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct || len(goFields) == 0 {
+		return "", false
+	}
+	field, ok := t.FieldByName(goFields[0])
+	if !ok {
+		return "", false
+	}
+	key := mapstructureKey(field)
+	if len(goFields) == 1 {
+		return key, true
+	}
+	rest, ok := configKeyPath(field.Type, goFields[1:])
+	if !ok {
+		return "", false
+	}
+	return key + "." + rest, true
+}
+
+// mapstructureKey returns the config key a field is addressed by: its
+// `mapstructure` tag name if set, otherwise its lowercased Go field name,
+// matching Viper's default field-to-key convention.
+func mapstructureKey(f reflect.StructField) string {
+	if tag, ok := f.Tag.Lookup("mapstructure"); ok {
+		if name, _, _ := strings.Cut(tag, ","); name != "" && name != "-" {
+			return name
+		}
+	}
+	return strings.ToLower(f.Name)
+}
+
+// applyDefaults walks v (a struct or pointer to struct) and, for every
+// zero-value field carrying a `default:"..."` tag, parses the tag text into
+// the field's type and assigns it. Nested structs are walked recursively so
+// a nested config block can declare its own defaults.
+func applyDefaults(v reflect.Value) error {
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return nil
+	}
+
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := v.Field(i)
+		if !field.CanSet() {
+			continue
+		}
+
+		if field.Kind() == reflect.Struct || (field.Kind() == reflect.Ptr && field.Type().Elem().Kind() == reflect.Struct) {
+			if err := applyDefaults(field); err != nil {
+				return err
+			}
+		}
+
+		tag, ok := t.Field(i).Tag.Lookup("default")
+		if !ok || !field.IsZero() {
+			continue
+		}
+
+		if err := setFromString(field, tag); err != nil {
+			return fmt.Errorf("default for field %s: %w", t.Field(i).Name, err)
+		}
+	}
+	return nil
+}
+
+// setFromString parses raw into field's underlying type and assigns it.
+// time.Duration is special-cased so `default:"30s"` works as expected
+// instead of being parsed as a bare integer.
+func setFromString(field reflect.Value, raw string) error {
+	if field.Type() == reflect.TypeOf(time.Duration(0)) {
+		d, err := time.ParseDuration(raw)
+		if err != nil {
+			return err
+		}
+		field.SetInt(int64(d))
+		return nil
+	}
+
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(raw)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return err
+		}
+		field.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			return err
+		}
+		field.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(raw, 10, 64)
+		if err != nil {
+			return err
+		}
+		field.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return err
+		}
+		field.SetFloat(f)
+	default:
+		return fmt.Errorf("unsupported default type %s", field.Kind())
+	}
+	return nil
+}
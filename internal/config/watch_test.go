@@ -0,0 +1,144 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// writeConfigMapLayout recreates the on-disk shape the kubelet produces for
+// a projected ConfigMap: a timestamped data directory holding the real
+// file, a "..data" symlink pointing at it, and the mounted file itself as a
+// symlink through "..data". It returns the mounted file's path.
+func writeConfigMapLayout(t *testing.T, dir, name, content string) string {
+	t.Helper()
+
+	dataDir := filepath.Join(dir, "..2024_01_01_000000")
+	if err := os.Mkdir(dataDir, 0o755); err != nil {
+		t.Fatalf("mkdir data dir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dataDir, name), []byte(content), 0o644); err != nil {
+		t.Fatalf("write data file: %v", err)
+	}
+	if err := os.Symlink(filepath.Base(dataDir), filepath.Join(dir, "..data")); err != nil {
+		t.Fatalf("symlink ..data: %v", err)
+	}
+	mounted := filepath.Join(dir, name)
+	if err := os.Symlink(filepath.Join("..data", name), mounted); err != nil {
+		t.Fatalf("symlink mounted file: %v", err)
+	}
+	return mounted
+}
+
+// rotateConfigMapLayout performs the kubelet's atomic rotation: write a new
+// timestamped directory, then atomically repoint "..data" at it. The
+// "..data" symlink event lands directly in dir, not in the old or new data
+// directory, and never touches the mounted file's own symlink.
+func rotateConfigMapLayout(t *testing.T, dir, name, content string) {
+	t.Helper()
+
+	newDataDir := filepath.Join(dir, "..2024_01_02_000000")
+	if err := os.Mkdir(newDataDir, 0o755); err != nil {
+		t.Fatalf("mkdir new data dir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(newDataDir, name), []byte(content), 0o644); err != nil {
+		t.Fatalf("write new data file: %v", err)
+	}
+
+	tmpLink := filepath.Join(dir, "..data_tmp")
+	if err := os.Symlink(filepath.Base(newDataDir), tmpLink); err != nil {
+		t.Fatalf("symlink ..data_tmp: %v", err)
+	}
+	if err := os.Rename(tmpLink, filepath.Join(dir, "..data")); err != nil {
+		t.Fatalf("rename ..data: %v", err)
+	}
+}
+
+func TestWatchFile_FiresOnConfigMapSymlinkRotation(t *testing.T) {
+	dir := t.TempDir()
+	mounted := writeConfigMapLayout(t, dir, "app.yaml", "a: 1")
+
+	changed := make(chan struct{}, 1)
+	watcher, err := watchFile(mounted, nil, func() {
+		select {
+		case changed <- struct{}{}:
+		default:
+		}
+	})
+	if err != nil {
+		t.Fatalf("watchFile: %v", err)
+	}
+	defer watcher.Close()
+
+	rotateConfigMapLayout(t, dir, "app.yaml", "a: 2")
+
+	select {
+	case <-changed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("watchFile did not fire for a ConfigMap ..data symlink rotation")
+	}
+}
+
+func TestWatchFile_IgnoresUnrelatedFileInSameDirectory(t *testing.T) {
+	dir := t.TempDir()
+	mounted := writeConfigMapLayout(t, dir, "app.yaml", "a: 1")
+
+	changed := make(chan struct{}, 1)
+	watcher, err := watchFile(mounted, nil, func() {
+		select {
+		case changed <- struct{}{}:
+		default:
+		}
+	})
+	if err != nil {
+		t.Fatalf("watchFile: %v", err)
+	}
+	defer watcher.Close()
+
+	if err := os.WriteFile(filepath.Join(dir, "unrelated.yaml"), []byte("x: 1"), 0o644); err != nil {
+		t.Fatalf("write unrelated file: %v", err)
+	}
+
+	select {
+	case <-changed:
+		t.Fatal("watchFile fired for an unrelated file in the same directory")
+	case <-time.After(300 * time.Millisecond):
+	}
+}
+
+// TestLoader_Load_ReloadsAutomaticallyOnWatchedFileSource confirms a custom
+// Loader built directly with NewFileSource, wrapped in NewWatchedFileSource,
+// gets the same automatic reload on file change that LoadConfig's own
+// default pipeline gets, without reimplementing watchFile's wiring.
+func TestLoader_Load_ReloadsAutomaticallyOnWatchedFileSource(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.yaml")
+	if err := os.WriteFile(path, []byte("value: 1\n"), 0o644); err != nil {
+		t.Fatalf("write config file: %v", err)
+	}
+
+	loader := NewLoader("watched-file", nil)
+	loader.Register(NewWatchedFileSource(NewFileSource(path), nil))
+
+	var target loadTarget
+	if err := loader.Load(&target); err != nil {
+		t.Fatalf("initial Load: %v", err)
+	}
+	if target.Value != 1 {
+		t.Fatalf("expected initial value 1, got %d", target.Value)
+	}
+
+	if err := os.WriteFile(path, []byte("value: 2\n"), 0o644); err != nil {
+		t.Fatalf("rewrite config file: %v", err)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		if v, _ := loader.Describe().Values["value"].(int); v == 2 {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatal("Loader did not reload automatically after a WatchedFileSource change")
+}